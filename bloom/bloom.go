@@ -0,0 +1,99 @@
+// Package bloom implements a fixed-size Bloom filter used as a fast,
+// in-memory membership prefilter ahead of slower exact lookups (e.g. an
+// on-disk index).
+package bloom
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// Filter is a Bloom filter sized for a target element count and
+// false-positive rate. It uses the Kirsch-Mitzenmacher double-hashing
+// scheme (h1 + i*h2) to derive k independent bit positions from two
+// 64-bit FNV hashes, avoiding k separate hash computations per operation.
+type Filter struct {
+	bits []uint64
+	m    uint64 // number of bits
+	k    uint64 // number of hash functions
+}
+
+// New sizes a Filter for n elements at a target false-positive rate fpr,
+// using the standard m = -n*ln(p)/(ln(2)^2) and k = (m/n)*ln(2) formulas.
+func New(n uint64, fpr float64) *Filter {
+	if n == 0 {
+		n = 1
+	}
+	if fpr <= 0 || fpr >= 1 {
+		fpr = 0.01
+	}
+
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpr) / (math.Ln2 * math.Ln2)))
+	if m == 0 {
+		m = 1
+	}
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k == 0 {
+		k = 1
+	}
+
+	return &Filter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// Add sets the k bits derived from data.
+func (f *Filter) Add(data []byte) {
+	h1, h2 := doubleHash(data)
+	for i := uint64(0); i < f.k; i++ {
+		f.setBit((h1 + i*h2) % f.m)
+	}
+}
+
+// MaybeContains reports whether data might have been added. false is a
+// definite answer; true may be a false positive and should be confirmed
+// against an exact source before being trusted.
+func (f *Filter) MaybeContains(data []byte) bool {
+	h1, h2 := doubleHash(data)
+	for i := uint64(0); i < f.k; i++ {
+		if !f.getBit((h1 + i*h2) % f.m) {
+			return false
+		}
+	}
+	return true
+}
+
+// Bits and K return the filter's bit-vector size and hash count, mostly
+// useful for reporting/tuning.
+func (f *Filter) Bits() uint64 { return f.m }
+func (f *Filter) K() uint64    { return f.k }
+
+func (f *Filter) setBit(i uint64) {
+	f.bits[i/64] |= 1 << (i % 64)
+}
+
+func (f *Filter) getBit(i uint64) bool {
+	return f.bits[i/64]&(1<<(i%64)) != 0
+}
+
+// doubleHash returns two independent 64-bit hashes of data, combined as
+// h1 + i*h2 to derive the filter's k bit positions.
+func doubleHash(data []byte) (uint64, uint64) {
+	h1 := fnv.New64a()
+	h1.Write(data)
+	h2 := fnv.New64()
+	h2.Write(data)
+	return h1.Sum64(), h2.Sum64()
+}
+
+// Hash64 returns the same primary 64-bit hash Add/MaybeContains use
+// internally as h1. Callers that pair a Filter with an exact secondary
+// index (e.g. a sorted on-disk file of hashed keys) should key that index
+// with Hash64 so both structures agree on identity.
+func Hash64(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
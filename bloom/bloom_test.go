@@ -0,0 +1,29 @@
+package bloom
+
+import "testing"
+
+func TestFilterNoFalseNegatives(t *testing.T) {
+	f := New(1000, 0.01)
+	values := [][]byte{[]byte("555-1111"), []byte("555-2222"), []byte("555-3333")}
+	for _, v := range values {
+		f.Add(v)
+	}
+	for _, v := range values {
+		if !f.MaybeContains(v) {
+			t.Errorf("MaybeContains(%s) = false, want true (false negatives are not allowed)", v)
+		}
+	}
+}
+
+func TestHash64Deterministic(t *testing.T) {
+	a := Hash64([]byte("555-1234"))
+	b := Hash64([]byte("555-1234"))
+	if a != b {
+		t.Errorf("Hash64 is not deterministic: %d != %d", a, b)
+	}
+
+	c := Hash64([]byte("555-5678"))
+	if a == c {
+		t.Errorf("expected different hashes for different input")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math/rand"
+)
+
+// syntheticHeader returns a header with enough named columns to exercise
+// the truncate, combine, and intersect pipelines (first_name/last_name,
+// phone_number, partition_key), padded out to cols with filler columns.
+func syntheticHeader(cols int) []string {
+	header := []string{"id", "first_name", "last_name", "phone_number", "partition_key"}
+	for len(header) < cols {
+		header = append(header, fmt.Sprintf("col%d", len(header)))
+	}
+	return header[:cols]
+}
+
+// generateCSV writes a deterministic CSV of rows rows and cols columns to
+// w, using rng for every random value so that two runs with the same seed
+// produce byte-identical output. partitionCardinality bounds how many
+// distinct partition_key values appear, to model low- or high-cardinality
+// partitioning workloads.
+func generateCSV(w *csv.Writer, rows, cols, partitionCardinality int, rng *rand.Rand) error {
+	header := syntheticHeader(cols)
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	row := make([]string, len(header))
+	for i := 0; i < rows; i++ {
+		for c, name := range header {
+			switch name {
+			case "id":
+				row[c] = fmt.Sprintf("%d", i)
+			case "first_name":
+				row[c] = fmt.Sprintf("First%d", rng.Intn(rows+1))
+			case "last_name":
+				row[c] = fmt.Sprintf("Last%d", rng.Intn(rows+1))
+			case "phone_number":
+				row[c] = fmt.Sprintf("555%07d", rng.Intn(10_000_000))
+			case "partition_key":
+				row[c] = fmt.Sprintf("part-%d", rng.Intn(partitionCardinality))
+			default:
+				row[c] = fmt.Sprintf("v%d", rng.Intn(1000))
+			}
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,298 @@
+// Command bench runs the truncate, combine, and intersect pipelines
+// against synthetic CSV input and reports throughput and resource usage,
+// to help tune -concurrency and -bufsize for a given workload.
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/seriousconsult/glue/csvpipe"
+)
+
+var (
+	pipelineFlag    = flag.String("pipeline", "truncate", "pipeline to benchmark: truncate, combine, or intersect")
+	rowsFlag        = flag.Int("rows", 1_000_000, "number of synthetic rows to generate")
+	colsFlag        = flag.Int("cols", 10, "number of columns in the synthetic CSV")
+	partitionsFlag  = flag.Int("partitions", 100, "number of distinct partition_key values in the synthetic CSV")
+	seedFlag        = flag.Int64("seed", 1, "PRNG seed, for reproducible runs")
+	concurrencyFlag = flag.Int("concurrency", runtime.NumCPU(), "number of transform worker goroutines")
+	bufsizeFlag     = flag.Int("bufsize", runtime.NumCPU(), "buffer size of the channel between the worker pool and the sink")
+	cpuprofileFlag  = flag.String("cpuprofile", "", "write a CPU profile to this file")
+	memprofileFlag  = flag.String("memprofile", "", "write a heap profile to this file")
+	traceFlag       = flag.String("trace", "", "write an execution trace to this file")
+)
+
+func main() {
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	flag.Parse()
+
+	if *cpuprofileFlag != "" {
+		f, err := os.Create(*cpuprofileFlag)
+		if err != nil {
+			log.Fatalf("Error creating CPU profile: %v", err)
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			log.Fatalf("Error starting CPU profile: %v", err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	if *traceFlag != "" {
+		f, err := os.Create(*traceFlag)
+		if err != nil {
+			log.Fatalf("Error creating trace file: %v", err)
+		}
+		defer f.Close()
+		if err := trace.Start(f); err != nil {
+			log.Fatalf("Error starting trace: %v", err)
+		}
+		defer trace.Stop()
+	}
+
+	inputPath, err := generateInput(*rowsFlag, *colsFlag, *partitionsFlag, *seedFlag)
+	if err != nil {
+		log.Fatalf("Error generating synthetic input: %v", err)
+	}
+	defer os.Remove(inputPath)
+
+	info, err := os.Stat(inputPath)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	result, err := runBenchmark(*pipelineFlag, inputPath, *concurrencyFlag, *bufsizeFlag)
+	if err != nil {
+		log.Fatalf("Error running %s pipeline: %v", *pipelineFlag, err)
+	}
+
+	if *memprofileFlag != "" {
+		f, err := os.Create(*memprofileFlag)
+		if err != nil {
+			log.Fatalf("Error creating heap profile: %v", err)
+		}
+		defer f.Close()
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			log.Fatalf("Error writing heap profile: %v", err)
+		}
+	}
+
+	reportResult(*pipelineFlag, *rowsFlag, info.Size(), *concurrencyFlag, result)
+}
+
+// generateInput writes a synthetic CSV to a temp file and returns its
+// path; the caller is responsible for removing it.
+func generateInput(rows, cols, partitions int, seed int64) (string, error) {
+	file, err := os.CreateTemp("", "bench-input-*.csv")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	rng := rand.New(rand.NewSource(seed))
+	if err := generateCSV(csv.NewWriter(file), rows, cols, partitions, rng); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// benchResult holds the measurements taken during a single pipeline run.
+type benchResult struct {
+	elapsed       time.Duration
+	rowsOut       int64
+	readWait      time.Duration
+	sendWait      time.Duration
+	peakHeapBytes uint64
+}
+
+func runBenchmark(pipelineName, inputPath string, concurrency, bufSize int) (benchResult, error) {
+	source, err := csvpipe.NewFileSource(inputPath, csvpipe.DefaultDialect())
+	if err != nil {
+		return benchResult{}, err
+	}
+
+	transform, err := transformFor(pipelineName, source.Header())
+	if err != nil {
+		return benchResult{}, err
+	}
+
+	sink := csvpipe.NewSink(csv.NewWriter(io.Discard), nil)
+
+	stopSampler, peakHeap := sampleHeap()
+	start := time.Now()
+	readWait, sendWait, err := instrumentedRun(source, sink, transform, concurrency, bufSize)
+	elapsed := time.Since(start)
+	stopSampler()
+	if err != nil {
+		return benchResult{}, err
+	}
+
+	return benchResult{
+		elapsed:       elapsed,
+		rowsOut:       sink.Count(),
+		readWait:      readWait,
+		sendWait:      sendWait,
+		peakHeapBytes: peakHeap(),
+	}, nil
+}
+
+// transformFor builds the same kind of Transform the truncate/combine/intersect
+// commands use (KeepColumns, CombineColumns, FilterByColumnSet), with columns
+// and a one-value set chosen for the synthetic header. Every pipeline is run
+// through instrumentedRun; intersect's batched streamAndCount and its file1
+// loading are not measured.
+func transformFor(pipelineName string, header []string) (csvpipe.Transform, error) {
+	switch pipelineName {
+	case "truncate":
+		return csvpipe.KeepColumns([]int{0, 1, 2}), nil
+	case "combine":
+		col1, col2 := indexOf(header, "first_name"), indexOf(header, "last_name")
+		if col1 == -1 || col2 == -1 {
+			return nil, fmt.Errorf("synthetic header is missing first_name/last_name")
+		}
+		return csvpipe.CombineColumns(col1, col2), nil
+	case "intersect":
+		col := indexOf(header, "phone_number")
+		if col == -1 {
+			return nil, fmt.Errorf("synthetic header is missing phone_number")
+		}
+		return csvpipe.FilterByColumnSet(col, map[string]struct{}{"5550000000": {}}), nil
+	default:
+		return nil, fmt.Errorf("unknown pipeline %q (want truncate, combine, or intersect)", pipelineName)
+	}
+}
+
+func indexOf(header []string, name string) int {
+	for i, col := range header {
+		if strings.TrimSpace(strings.ToLower(col)) == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// instrumentedRun mirrors csvpipe.Pipeline.Run but also accumulates, across
+// all workers, the time spent blocked receiving from the source and
+// blocked sending to the sink stage, as an approximation of per-stage
+// channel wait time.
+func instrumentedRun(source csvpipe.Source, sink *csvpipe.Sink, transform csvpipe.Transform, concurrency, bufSize int) (readWait, sendWait time.Duration, err error) {
+	processedChan := make(chan []string, bufSize)
+	var readWaitNanos, sendWaitNanos int64
+
+	var workerWg sync.WaitGroup
+	workerWg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer workerWg.Done()
+			rows := source.Rows()
+			for {
+				waitStart := time.Now()
+				row, ok := <-rows
+				atomic.AddInt64(&readWaitNanos, int64(time.Since(waitStart)))
+				if !ok {
+					return
+				}
+
+				out, keep, terr := transform(row)
+				if terr != nil || !keep {
+					continue
+				}
+
+				sendStart := time.Now()
+				processedChan <- out
+				atomic.AddInt64(&sendWaitNanos, int64(time.Since(sendStart)))
+			}
+		}()
+	}
+
+	go func() {
+		workerWg.Wait()
+		close(processedChan)
+	}()
+
+	for row := range processedChan {
+		if werr := sink.Write(row); werr != nil {
+			err = werr
+		}
+	}
+	if ferr := sink.Flush(); ferr != nil && err == nil {
+		err = ferr
+	}
+
+	return time.Duration(atomic.LoadInt64(&readWaitNanos)), time.Duration(atomic.LoadInt64(&sendWaitNanos)), err
+}
+
+// sampleHeap polls runtime.MemStats in the background and tracks the
+// highest HeapAlloc observed, as an approximation of peak memory use. It
+// samples immediately on start and again on stop, so runs shorter than one
+// tick still get a real reading instead of 0.
+func sampleHeap() (stop func(), peak func() uint64) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	var mu sync.Mutex
+	var mem runtime.MemStats
+	var peakBytes uint64
+
+	record := func() {
+		runtime.ReadMemStats(&mem)
+		mu.Lock()
+		if mem.HeapAlloc > peakBytes {
+			peakBytes = mem.HeapAlloc
+		}
+		mu.Unlock()
+	}
+
+	go func() {
+		defer close(stopped)
+		record()
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				record()
+				return
+			case <-ticker.C:
+				record()
+			}
+		}
+	}()
+
+	stop = func() {
+		close(done)
+		<-stopped // wait for the final sample before peak() is read
+	}
+	peak = func() uint64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return peakBytes
+	}
+	return stop, peak
+}
+
+func reportResult(pipelineName string, rowsIn int, inputBytes int64, concurrency int, r benchResult) {
+	seconds := r.elapsed.Seconds()
+	fmt.Printf("Pipeline:       %s\n", pipelineName)
+	fmt.Printf("Rows in/out:    %d / %d\n", rowsIn, r.rowsOut)
+	fmt.Printf("Elapsed:        %s\n", r.elapsed)
+	fmt.Printf("Rows/sec:       %.0f\n", float64(rowsIn)/seconds)
+	fmt.Printf("Bytes/sec:      %.0f\n", float64(inputBytes)/seconds)
+	fmt.Printf("Read-chan wait: %s (summed across %d workers)\n", r.readWait, concurrency)
+	fmt.Printf("Send-chan wait: %s (summed across %d workers)\n", r.sendWait, concurrency)
+	fmt.Printf("Peak heap:      %d bytes\n", r.peakHeapBytes)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/seriousconsult/glue/csvpipe"
+)
+
+// Default paths for the input and output CSV files, overridable with -in
+// and -out ("-" means stdin/stdout).
+const (
+	inputFilePath  = "large_file.csv"
+	outputFilePath = "combined_file.csv"
+)
+
+// Define the two column names to combine and the new column name.
+const (
+	column1Name        = "first_name"
+	column2Name        = "last_name"
+	combinedColumnName = "full_name"
+)
+
+var (
+	inFlag  = flag.String("in", inputFilePath, "input CSV path, or - for stdin")
+	outFlag = flag.String("out", outputFilePath, "output CSV path, or - for stdout")
+)
+
+func main() {
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	log.SetOutput(os.Stdout)
+
+	dialectFlags := csvpipe.RegisterDialectFlags(flag.CommandLine)
+	flag.Parse()
+
+	dialect, err := dialectFlags.Dialect()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	source, err := csvpipe.NewFileSource(*inFlag, dialect)
+	if err != nil {
+		log.Fatalf("Error opening input: %v", err)
+	}
+
+	// Find the column indices for the columns to combine; this requires a
+	// header, so -no-header is not supported here.
+	header := source.Header()
+	col1Index, col2Index := -1, -1
+	for i, col := range header {
+		switch strings.TrimSpace(strings.ToLower(col)) {
+		case column1Name:
+			col1Index = i
+		case column2Name:
+			col2Index = i
+		}
+	}
+	if col1Index == -1 || col2Index == -1 {
+		log.Fatalf("Could not find both '%s' and '%s' columns in the CSV header (combine requires a header row, so -no-header is not supported).", column1Name, column2Name)
+	}
+
+	// Create the new header for the output file.
+	newHeader := make([]string, len(header), len(header)+1)
+	copy(newHeader, header)
+	newHeader = append(newHeader, combinedColumnName)
+
+	output, err := csvpipe.OpenOutput(*outFlag)
+	if err != nil {
+		log.Fatalf("Error opening output: %v", err)
+	}
+	defer output.Close()
+
+	sink := csvpipe.NewSink(csvpipe.NewWriter(output, dialect), newHeader)
+
+	pipeline := csvpipe.NewPipeline(source, sink, csvpipe.CombineColumns(col1Index, col2Index))
+	if err := pipeline.Run(); err != nil {
+		log.Fatalf("Error running pipeline: %v", err)
+	}
+
+	fmt.Println("CSV processing complete.")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/seriousconsult/glue/csvpipe"
+)
+
+// streamAndCount streams rows from source, batches them to amortize
+// channel synchronization cost, and counts how many pass transform using a
+// worker pool.
+//
+// This replaces an earlier ad hoc reader/worker/ticker implementation that
+// had two races: the reader goroutine wasn't tracked by the same
+// WaitGroup as the workers, so wg.Wait() could return before the reader
+// had sent every record; and the progress reporter's final `done <- true`
+// on an unbuffered channel could deadlock if the ticker had already fired
+// and the reporter had returned. Here the reader has its own WaitGroup,
+// and the progress reporter is stopped via context cancellation instead of
+// a done channel.
+func streamAndCount(ctx context.Context, source csvpipe.Source, transform csvpipe.Transform, concurrency, bufferSize, batchSize int) (int64, error) {
+	batchesChan := make(chan [][]string, bufferSize)
+
+	var readerWg sync.WaitGroup
+	readerWg.Add(1)
+	go func() {
+		defer readerWg.Done()
+		defer close(batchesChan)
+
+		batch := make([][]string, 0, batchSize)
+		for row := range source.Rows() {
+			batch = append(batch, row)
+			if len(batch) >= batchSize {
+				batchesChan <- batch
+				batch = make([][]string, 0, batchSize)
+			}
+		}
+		if len(batch) > 0 {
+			batchesChan <- batch
+		}
+	}()
+
+	var commonCount int64
+	var workerWg sync.WaitGroup
+	workerWg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer workerWg.Done()
+			for batch := range batchesChan {
+				for _, row := range batch {
+					_, keep, err := transform(row)
+					if err != nil {
+						log.Printf("Warning: transform failed for row, skipping: %v", err)
+						continue
+					}
+					if keep {
+						atomic.AddInt64(&commonCount, 1)
+					}
+				}
+			}
+		}()
+	}
+
+	progressCtx, cancelProgress := context.WithCancel(ctx)
+	progressDone := make(chan struct{})
+	go reportProgress(progressCtx, &commonCount, progressDone)
+
+	readerWg.Wait()
+	workerWg.Wait()
+	cancelProgress()
+	<-progressDone
+
+	return atomic.LoadInt64(&commonCount), source.Err()
+}
+
+// reportProgress prints the running common-phone-number count every five
+// seconds until ctx is canceled.
+func reportProgress(ctx context.Context, commonCount *int64, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fmt.Printf("Current count of common phone numbers: %d\n", atomic.LoadInt64(commonCount))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+	"syscall"
+)
+
+// diskIndex is a sorted, mmap'd file of uint64 hashes used to verify
+// Bloom-filter hits without holding every hash in a Go map.
+type diskIndex struct {
+	file *os.File
+	data []byte
+}
+
+// buildDiskIndex sorts hashes and writes them as fixed-width
+// little-endian uint64s to a temporary file, then mmaps it for
+// binary-search lookups.
+func buildDiskIndex(hashes []uint64) (*diskIndex, error) {
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	file, err := os.CreateTemp("", "phoneindex-*.bin")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create index file: %w", err)
+	}
+
+	w := bufio.NewWriter(file)
+	var buf [8]byte
+	for _, h := range hashes {
+		binary.LittleEndian.PutUint64(buf[:], h)
+		if _, err := w.Write(buf[:]); err != nil {
+			file.Close()
+			os.Remove(file.Name())
+			return nil, fmt.Errorf("failed to write index file: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, fmt.Errorf("failed to flush index file: %w", err)
+	}
+
+	var data []byte
+	if size := len(hashes) * 8; size > 0 {
+		data, err = syscall.Mmap(int(file.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+			return nil, fmt.Errorf("failed to mmap index file: %w", err)
+		}
+	}
+
+	return &diskIndex{file: file, data: data}, nil
+}
+
+// Contains binary-searches the sorted mmap'd file for hash.
+func (idx *diskIndex) Contains(hash uint64) bool {
+	n := len(idx.data) / 8
+	lo, hi := 0, n-1
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		v := binary.LittleEndian.Uint64(idx.data[mid*8 : mid*8+8])
+		switch {
+		case v == hash:
+			return true
+		case v < hash:
+			lo = mid + 1
+		default:
+			hi = mid - 1
+		}
+	}
+	return false
+}
+
+// Close unmaps the index and removes its backing temporary file.
+func (idx *diskIndex) Close() error {
+	if idx.data != nil {
+		if err := syscall.Munmap(idx.data); err != nil {
+			return err
+		}
+	}
+	name := idx.file.Name()
+	if err := idx.file.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/seriousconsult/glue/bloom"
+	"github.com/seriousconsult/glue/csvpipe"
+)
+
+var (
+	bloomFlag = flag.Bool("bloom", false, "use an out-of-core Bloom-filter prefilter instead of loading all phone numbers into a map")
+	fprFlag   = flag.Float64("fpr", 0.01, "target Bloom filter false-positive rate (only with -bloom)")
+	nFlag     = flag.Uint64("n", 10_000_000, "expected number of unique phone numbers in file1, used to size the Bloom filter (only with -bloom)")
+
+	concurrencyFlag = flag.Int("concurrency", runtime.NumCPU(), "number of worker goroutines evaluating rows from file2")
+	bufferFlag      = flag.Int("buffer", runtime.NumCPU(), "capacity, in batches, of the channel between the reader and the worker pool")
+	batchFlag       = flag.Int("batch", 1024, "number of rows batched per channel send, to amortize synchronization cost")
+)
+
+func main() {
+	dialectFlags := csvpipe.RegisterDialectFlags(flag.CommandLine)
+	flag.Parse()
+	args := flag.Args()
+	if len(args) < 2 {
+		fmt.Printf("Usage: %s [-bloom] [-fpr rate] [-n count] [-concurrency N] [-buffer N] [-batch N] <file1.csv> <file2.csv>\n", os.Args[0])
+		os.Exit(1)
+	}
+	file1Path, file2Path := args[0], args[1]
+
+	dialect, err := dialectFlags.Dialect()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	ctx := context.Background()
+
+	var commonCount int64
+	if *bloomFlag {
+		commonCount, err = runBloomMode(ctx, file1Path, file2Path, dialect, *fprFlag, *nFlag, *concurrencyFlag, *bufferFlag, *batchFlag)
+	} else {
+		commonCount, err = runMapMode(ctx, file1Path, file2Path, dialect, *concurrencyFlag, *bufferFlag, *batchFlag)
+	}
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	fmt.Printf("\nDone.\n")
+	fmt.Printf("Total count of phone numbers found in both files: %d\n", commonCount)
+}
+
+// runMapMode is the original, in-memory strategy: load every unique phone
+// number from file1 into a map, then stream file2 against it.
+func runMapMode(ctx context.Context, file1Path, file2Path string, dialect csvpipe.Dialect, concurrency, bufferSize, batchSize int) (int64, error) {
+	fmt.Printf("Step 1: Loading unique phone numbers from '%s' into memory...\n", file1Path)
+	phoneSet, err := loadPhoneNumberSet(file1Path, dialect)
+	if err != nil {
+		return 0, err
+	}
+	fmt.Printf("Loaded %d unique phone numbers from '%s'.\n", len(phoneSet), file1Path)
+
+	fmt.Printf("\nStep 2: Streaming through '%s' with %d workers to find common phone numbers...\n", file2Path, concurrency)
+	source, err := csvpipe.NewFileSource(file2Path, dialect)
+	if err != nil {
+		return 0, err
+	}
+	columnIndex, err := phoneColumnIndex(source.Header())
+	if err != nil {
+		return 0, err
+	}
+
+	return streamAndCount(ctx, source, csvpipe.FilterByColumnSet(columnIndex, phoneSet), concurrency, bufferSize, batchSize)
+}
+
+// runBloomMode is the out-of-core strategy for file1s too large to hold as
+// a string map: a Bloom filter built from file1 prefilters file2's rows,
+// and Bloom hits are confirmed against a sorted on-disk index of file1's
+// hashed phone numbers to rule out false positives.
+func runBloomMode(ctx context.Context, file1Path, file2Path string, dialect csvpipe.Dialect, fpr float64, n uint64, concurrency, bufferSize, batchSize int) (int64, error) {
+	fmt.Printf("Step 1: Building a Bloom filter (fpr=%.4f, n=%d) and on-disk verification index from '%s'...\n", fpr, n, file1Path)
+	filter, index, indexed, err := buildBloomIndex(file1Path, dialect, fpr, n)
+	if err != nil {
+		return 0, err
+	}
+	defer index.Close()
+	fmt.Printf("Indexed %d unique phone numbers (%d bits, %d hash functions) from '%s'.\n", indexed, filter.Bits(), filter.K(), file1Path)
+
+	fmt.Printf("\nStep 2: Streaming through '%s' with %d workers, prefiltering with the Bloom filter...\n", file2Path, concurrency)
+	source, err := csvpipe.NewFileSource(file2Path, dialect)
+	if err != nil {
+		return 0, err
+	}
+	columnIndex, err := phoneColumnIndex(source.Header())
+	if err != nil {
+		return 0, err
+	}
+
+	verify := func(row []string) ([]string, bool, error) {
+		if columnIndex >= len(row) {
+			return nil, false, nil
+		}
+		phoneNumber := strings.TrimSpace(row[columnIndex])
+		if phoneNumber == "" {
+			return nil, false, nil
+		}
+		data := []byte(phoneNumber)
+		if !filter.MaybeContains(data) {
+			return nil, false, nil
+		}
+		// Bloom hit: confirm against the exact on-disk index before counting
+		// it, since the filter alone can false-positive.
+		return row, index.Contains(bloom.Hash64(data)), nil
+	}
+
+	return streamAndCount(ctx, source, verify, concurrency, bufferSize, batchSize)
+}
+
+// buildBloomIndex streams path once, adding every phone number to filter
+// and collecting the distinct hashes for the sorted on-disk verification
+// index. Duplicates are removed by hash, so no phone-number strings are
+// held in memory.
+func buildBloomIndex(path string, dialect csvpipe.Dialect, fpr float64, n uint64) (*bloom.Filter, *diskIndex, int, error) {
+	source, err := csvpipe.NewFileSource(path, dialect)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	columnIndex, err := phoneColumnIndex(source.Header())
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	filter := bloom.New(n, fpr)
+	hashes := make([]uint64, 0, n)
+	for row := range source.Rows() {
+		if columnIndex >= len(row) {
+			continue
+		}
+		phoneNumber := strings.TrimSpace(row[columnIndex])
+		if phoneNumber == "" {
+			continue
+		}
+		data := []byte(phoneNumber)
+		filter.Add(data)
+		hashes = append(hashes, bloom.Hash64(data))
+	}
+	if err := source.Err(); err != nil {
+		return nil, nil, 0, err
+	}
+
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+	unique := hashes[:0]
+	for _, h := range hashes {
+		if len(unique) == 0 || h != unique[len(unique)-1] {
+			unique = append(unique, h)
+		}
+	}
+	hashes = unique
+
+	index, err := buildDiskIndex(hashes)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return filter, index, len(hashes), nil
+}
+
+// phoneColumnIndex finds the "phone_number" column in header.
+func phoneColumnIndex(header []string) (int, error) {
+	for i, col := range header {
+		if strings.TrimSpace(strings.ToLower(col)) == "phone_number" {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("could not find 'phone_number' column")
+}
+
+// loadPhoneNumberSet reads every unique phone number in path into memory.
+func loadPhoneNumberSet(path string, dialect csvpipe.Dialect) (map[string]struct{}, error) {
+	source, err := csvpipe.NewFileSource(path, dialect)
+	if err != nil {
+		return nil, err
+	}
+	columnIndex, err := phoneColumnIndex(source.Header())
+	if err != nil {
+		return nil, err
+	}
+
+	phoneNumbers := make(map[string]struct{})
+	for row := range source.Rows() {
+		if columnIndex >= len(row) {
+			continue
+		}
+		phoneNumber := strings.TrimSpace(row[columnIndex])
+		if phoneNumber != "" {
+			phoneNumbers[phoneNumber] = struct{}{}
+		}
+	}
+	return phoneNumbers, source.Err()
+}
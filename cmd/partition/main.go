@@ -0,0 +1,295 @@
+package main
+
+import (
+	"container/list"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/seriousconsult/glue/csvpipe"
+)
+
+// Flags controlling the partition column, output location, and how many
+// partition files may be open at once before the router starts evicting
+// the least-recently-used writer.
+var (
+	columnFlag  = flag.String("column", "", "partition column, by name or 0-indexed position")
+	outDirFlag  = flag.String("out-dir", "partitions", "directory to write partition files into")
+	maxOpenFlag = flag.Int("max-open", 128, "maximum number of partition files open at once (0 = unlimited)")
+	bufSizeFlag = flag.Int("bufsize", 64, "buffered channel size per partition writer")
+)
+
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// sanitizePartitionValue turns a partition value into a safe filename
+// component, replacing anything that isn't alphanumeric (or . _ -) with "_".
+func sanitizePartitionValue(value string) string {
+	sanitized := unsafeFilenameChars.ReplaceAllString(value, "_")
+	if sanitized == "" {
+		sanitized = "_empty_"
+	}
+	return sanitized
+}
+
+func main() {
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	dialectFlags := csvpipe.RegisterDialectFlags(flag.CommandLine)
+	flag.Parse()
+
+	if *columnFlag == "" {
+		log.Fatalf("Error: -column is required")
+	}
+	inputPaths := flag.Args()
+	if len(inputPaths) == 0 {
+		log.Fatalf("Usage: %s -column <name|index> [options] <input.csv> [more.csv ...]", os.Args[0])
+	}
+
+	dialect, err := dialectFlags.Dialect()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	firstSource, err := csvpipe.NewFileSource(inputPaths[0], dialect)
+	if err != nil {
+		log.Fatalf("Error opening %s: %v", inputPaths[0], err)
+	}
+	columnIndex, err := resolveColumnIndex(firstSource.Header(), *columnFlag)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	if err := os.MkdirAll(*outDirFlag, 0755); err != nil {
+		log.Fatalf("Error creating output directory %s: %v", *outDirFlag, err)
+	}
+
+	if header := firstSource.Header(); header != nil {
+		fmt.Printf("Partitioning %d input file(s) on column %d (%q) into %s...\n", len(inputPaths), columnIndex, header[columnIndex], *outDirFlag)
+	} else {
+		fmt.Printf("Partitioning %d input file(s) on column %d into %s...\n", len(inputPaths), columnIndex, *outDirFlag)
+	}
+
+	rowsChan := make(chan []string, *bufSizeFlag)
+
+	var readerWg sync.WaitGroup
+	readerWg.Add(1)
+	go readInputs(firstSource, inputPaths[1:], dialect, rowsChan, &readerWg)
+
+	r := newRouter(*outDirFlag, *maxOpenFlag, *bufSizeFlag, dialect, csvpipe.PartitionBy(columnIndex))
+	r.run(rowsChan)
+
+	readerWg.Wait()
+	fmt.Printf("Partitioning complete. Wrote %d partitions to %s.\n", r.totalPartitions(), *outDirFlag)
+}
+
+// resolveColumnIndex interprets columnArg as a 0-indexed column position if
+// it parses as an integer, otherwise it is matched case-insensitively
+// against the header. header is nil when -no-header is set, in which case
+// only a numeric columnArg can be resolved, bounds-checked against rows
+// instead (csvpipe.PartitionBy reports out-of-range columns per row).
+func resolveColumnIndex(header []string, columnArg string) (int, error) {
+	if idx, err := strconv.Atoi(columnArg); err == nil {
+		if idx < 0 {
+			return 0, fmt.Errorf("column index %d is negative", idx)
+		}
+		if header != nil && idx >= len(header) {
+			return 0, fmt.Errorf("column index %d out of range for header with %d columns", idx, len(header))
+		}
+		return idx, nil
+	}
+	if header == nil {
+		return 0, fmt.Errorf("column %q is not a number, but -no-header means there is no header to match it against", columnArg)
+	}
+	for i, col := range header {
+		if strings.TrimSpace(strings.ToLower(col)) == strings.ToLower(columnArg) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("could not find column %q in header", columnArg)
+}
+
+// readInputs drains first, then opens and drains each remaining path in
+// turn, sending every row to rowsChan.
+func readInputs(first csvpipe.Source, restPaths []string, dialect csvpipe.Dialect, rowsChan chan<- []string, wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer close(rowsChan)
+
+	drainSource(first, rowsChan)
+	for _, path := range restPaths {
+		source, err := csvpipe.NewFileSource(path, dialect)
+		if err != nil {
+			log.Printf("Error opening %s: %v", path, err)
+			continue
+		}
+		drainSource(source, rowsChan)
+	}
+}
+
+func drainSource(source csvpipe.Source, rowsChan chan<- []string) {
+	for row := range source.Rows() {
+		rowsChan <- row
+	}
+	if err := source.Err(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// writerEntry is a single partition's channel and the writer goroutine
+// draining it.
+type writerEntry struct {
+	value  string
+	rowsCh chan []string
+	done   chan struct{}
+}
+
+// router fans rows out to one writer goroutine per distinct partition
+// value, lazily creating writers and evicting the least-recently-used one
+// once maxOpen writers are active.
+type router struct {
+	outDir       string
+	maxOpen      int
+	bufSize      int
+	dialect      csvpipe.Dialect
+	partitionKey func(row []string) (string, error)
+
+	writers    map[string]*writerEntry
+	lru        *list.List
+	lruElems   map[string]*list.Element
+	seenValues map[string]bool
+	writerWg   sync.WaitGroup
+}
+
+func newRouter(outDir string, maxOpen, bufSize int, dialect csvpipe.Dialect, partitionKey func(row []string) (string, error)) *router {
+	return &router{
+		outDir:       outDir,
+		maxOpen:      maxOpen,
+		bufSize:      bufSize,
+		dialect:      dialect,
+		partitionKey: partitionKey,
+		writers:      make(map[string]*writerEntry),
+		lru:          list.New(),
+		lruElems:     make(map[string]*list.Element),
+		seenValues:   make(map[string]bool),
+	}
+}
+
+// run consumes rowsChan until it is closed, routing each row to its
+// partition writer, then closes all remaining writers and waits for them
+// to flush.
+func (r *router) run(rowsChan <-chan []string) {
+	for row := range rowsChan {
+		value, err := r.partitionKey(row)
+		if err != nil {
+			log.Printf("Warning: %v, skipping row", err)
+			continue
+		}
+		r.route(value, row)
+	}
+	r.closeAll()
+	r.writerWg.Wait()
+}
+
+func (r *router) route(value string, row []string) {
+	entry, ok := r.writers[value]
+	if ok {
+		entry.rowsCh <- row
+		r.lru.MoveToFront(r.lruElems[value])
+		return
+	}
+
+	if r.maxOpen > 0 && len(r.writers) >= r.maxOpen {
+		r.evictLRU()
+	}
+	entry = r.openWriter(value)
+	entry.rowsCh <- row
+}
+
+func (r *router) openWriter(value string) *writerEntry {
+	entry := &writerEntry{
+		value:  value,
+		rowsCh: make(chan []string, r.bufSize),
+		done:   make(chan struct{}),
+	}
+	r.writers[value] = entry
+	r.lruElems[value] = r.lru.PushFront(value)
+
+	appendMode := r.seenValues[value]
+	r.seenValues[value] = true
+
+	r.writerWg.Add(1)
+	go runPartitionWriter(entry, r.outDir, r.dialect, appendMode, &r.writerWg)
+	return entry
+}
+
+// evictLRU flushes and closes the least-recently-used writer to free up a
+// file descriptor for a new partition.
+func (r *router) evictLRU() {
+	back := r.lru.Back()
+	if back == nil {
+		return
+	}
+	r.closeWriter(back.Value.(string))
+}
+
+func (r *router) closeWriter(value string) {
+	entry, ok := r.writers[value]
+	if !ok {
+		return
+	}
+	close(entry.rowsCh)
+	<-entry.done // wait for the writer to flush and close its file
+
+	delete(r.writers, value)
+	r.lru.Remove(r.lruElems[value])
+	delete(r.lruElems, value)
+}
+
+func (r *router) closeAll() {
+	for value := range r.writers {
+		r.closeWriter(value)
+	}
+}
+
+func (r *router) totalPartitions() int {
+	return len(r.seenValues)
+}
+
+// runPartitionWriter owns the *csv.Writer for a single partition, draining
+// entry.rowsCh until it is closed, then flushing and closing the file.
+// appendMode is set when this partition was evicted earlier and is being
+// reopened, so the file is appended to rather than truncated.
+func runPartitionWriter(entry *writerEntry, outDir string, dialect csvpipe.Dialect, appendMode bool, wg *sync.WaitGroup) {
+	defer wg.Done()
+	defer close(entry.done)
+
+	path := filepath.Join(outDir, sanitizePartitionValue(entry.value)+".csv")
+	flags := os.O_CREATE | os.O_WRONLY
+	if appendMode {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+
+	file, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		log.Printf("Error opening partition file %s: %v", path, err)
+		for range entry.rowsCh { // drain so the router doesn't block on a dead writer
+		}
+		return
+	}
+	defer file.Close()
+
+	writer := csvpipe.NewWriter(file, dialect)
+	defer writer.Flush()
+
+	for row := range entry.rowsCh {
+		if err := writer.Write(row); err != nil {
+			log.Printf("Error writing row to %s: %v", path, err)
+		}
+	}
+}
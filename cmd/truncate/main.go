@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/seriousconsult/glue/csvpipe"
+)
+
+// Default paths for the input and output CSV files, overridable with -in
+// and -out ("-" means stdin/stdout).
+const (
+	inputFilePath  = "large_file.csv"
+	outputFilePath = "truncated_file.csv"
+)
+
+// Define which columns you want to keep (0-indexed).
+var columnsToKeep = []int{0, 2, 12, 55, 57}
+
+var (
+	inFlag  = flag.String("in", inputFilePath, "input CSV path, or - for stdin")
+	outFlag = flag.String("out", outputFilePath, "output CSV path, or - for stdout")
+)
+
+func main() {
+	// Add line number and file name to log messages for better debugging.
+	log.SetFlags(log.LstdFlags | log.Lshortfile)
+	log.SetOutput(os.Stdout)
+
+	dialectFlags := csvpipe.RegisterDialectFlags(flag.CommandLine)
+	flag.Parse()
+
+	dialect, err := dialectFlags.Dialect()
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	source, err := csvpipe.NewFileSource(*inFlag, dialect)
+	if err != nil {
+		log.Fatalf("Error opening input: %v", err)
+	}
+
+	output, err := csvpipe.OpenOutput(*outFlag)
+	if err != nil {
+		log.Fatalf("Error opening output: %v", err)
+	}
+	defer output.Close()
+
+	sink := csvpipe.NewSink(csvpipe.NewWriter(output, dialect), nil)
+
+	pipeline := csvpipe.NewPipeline(source, sink, csvpipe.KeepColumns(columnsToKeep))
+	if err := pipeline.Run(); err != nil {
+		log.Fatalf("Error running pipeline: %v", err)
+	}
+
+	fmt.Printf("CSV processing complete. Total rows processed: %d\n", sink.Count())
+}
@@ -0,0 +1,227 @@
+// Package csvpipe provides the reader-goroutine/worker-pool/writer-goroutine
+// scaffolding shared by the CSV command-line tools, so each tool only needs
+// to supply a Source, a Sink, and a Transform.
+package csvpipe
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Source produces CSV rows on a channel. Rows must stop once the channel is
+// closed; Err reports any error encountered while reading, and Header
+// returns the header row read before streaming began (nil if there was
+// none).
+type Source interface {
+	Rows() <-chan []string
+	Err() error
+	Header() []string
+}
+
+// fileSource is the Source returned by NewFileSource: it reads a CSV file
+// on a dedicated goroutine and streams rows to buffered channel.
+type fileSource struct {
+	header []string
+	rowsCh chan []string
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewFileSource opens path (or stdin, if path is "-") with dialect applied,
+// reads its header unless dialect.NoHeader is set, and starts a reader
+// goroutine that streams the remaining rows. Malformed rows are logged and
+// skipped, matching the existing tools' behavior.
+func NewFileSource(path string, dialect Dialect) (Source, error) {
+	input, err := OpenInput(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(input)
+	dialect.applyToReader(reader)
+
+	var header []string
+	if !dialect.NoHeader {
+		header, err = reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				header = nil
+			} else {
+				input.Close()
+				return nil, fmt.Errorf("failed to read header from %s: %w", path, err)
+			}
+		}
+	}
+
+	src := &fileSource{
+		header: header,
+		rowsCh: make(chan []string, runtime.NumCPU()),
+	}
+	go src.read(path, input, reader)
+	return src, nil
+}
+
+func (s *fileSource) read(path string, input io.ReadCloser, reader *csv.Reader) {
+	defer close(s.rowsCh)
+	defer input.Close()
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Printf("Warning: failed to read row from %s, skipping: %v", path, err)
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+			continue
+		}
+		s.rowsCh <- record
+	}
+}
+
+func (s *fileSource) Rows() <-chan []string { return s.rowsCh }
+
+func (s *fileSource) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *fileSource) Header() []string { return s.header }
+
+// Sink wraps a *csv.Writer, writing its header at most once, the first time
+// Write is called.
+type Sink struct {
+	writer      *csv.Writer
+	header      []string
+	wroteHeader bool
+	mu          sync.Mutex
+	count       int64
+}
+
+// NewSink wraps writer, writing header before the first row. header may be
+// nil, in which case no header is written.
+func NewSink(writer *csv.Writer, header []string) *Sink {
+	return &Sink{writer: writer, header: header}
+}
+
+// Write writes row, writing the header first if this is the first call.
+func (s *Sink) Write(row []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.wroteHeader {
+		s.wroteHeader = true
+		if s.header != nil {
+			if err := s.writer.Write(s.header); err != nil {
+				return fmt.Errorf("failed to write header: %w", err)
+			}
+		}
+	}
+	if err := s.writer.Write(row); err != nil {
+		return fmt.Errorf("failed to write row: %w", err)
+	}
+	atomic.AddInt64(&s.count, 1)
+	return nil
+}
+
+// Flush flushes the underlying writer and returns any error it accumulated.
+func (s *Sink) Flush() error {
+	s.writer.Flush()
+	return s.writer.Error()
+}
+
+// Count returns the number of data rows (excluding the header) written so
+// far.
+func (s *Sink) Count() int64 {
+	return atomic.LoadInt64(&s.count)
+}
+
+// Transform maps one input row to one output row. A transform returns
+// keep=false to drop the row, or a non-nil error to abort the row (logged
+// and skipped by the pipeline).
+type Transform func(row []string) (out []string, keep bool, err error)
+
+// Pipeline wires a Source through a worker pool of Transform calls into a
+// Sink.
+type Pipeline struct {
+	source     Source
+	sink       *Sink
+	transform  Transform
+	numWorkers int
+	bufSize    int
+}
+
+// NewPipeline builds a Pipeline with default worker count and channel
+// buffer size of runtime.NumCPU(); override with WithWorkers/WithBufferSize.
+func NewPipeline(source Source, sink *Sink, transform Transform) *Pipeline {
+	return &Pipeline{
+		source:     source,
+		sink:       sink,
+		transform:  transform,
+		numWorkers: runtime.NumCPU(),
+		bufSize:    runtime.NumCPU(),
+	}
+}
+
+// WithWorkers sets the number of transform worker goroutines.
+func (p *Pipeline) WithWorkers(n int) *Pipeline {
+	p.numWorkers = n
+	return p
+}
+
+// WithBufferSize sets the buffer size of the channel between the worker
+// pool and the sink.
+func (p *Pipeline) WithBufferSize(n int) *Pipeline {
+	p.bufSize = n
+	return p
+}
+
+// Run drains the source through the worker pool and sink, and blocks until
+// every row has been written and flushed. It returns the first sink error
+// encountered, or the source's accumulated error if none.
+func (p *Pipeline) Run() error {
+	processedChan := make(chan []string, p.bufSize)
+
+	var workerWg sync.WaitGroup
+	workerWg.Add(p.numWorkers)
+	for i := 0; i < p.numWorkers; i++ {
+		go func() {
+			defer workerWg.Done()
+			for row := range p.source.Rows() {
+				out, keep, err := p.transform(row)
+				if err != nil {
+					log.Printf("Warning: transform failed for row, skipping: %v", err)
+					continue
+				}
+				if !keep {
+					continue
+				}
+				processedChan <- out
+			}
+		}()
+	}
+
+	go func() {
+		workerWg.Wait()
+		close(processedChan)
+	}()
+
+	for row := range processedChan {
+		if err := p.sink.Write(row); err != nil {
+			return err
+		}
+	}
+	if err := p.sink.Flush(); err != nil {
+		return err
+	}
+	return p.source.Err()
+}
@@ -0,0 +1,160 @@
+package csvpipe
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// Dialect describes the CSV formatting knobs applied to both the reader
+// and the writer, so a tool can process TSVs, pipe-delimited files, or
+// other CSV variants without code changes.
+type Dialect struct {
+	Delim           rune // field delimiter
+	Comment         rune // comment character; rows starting with it are skipped. 0 disables.
+	LazyQuotes      bool
+	FieldsPerRecord int  // passed straight through to csv.Reader.FieldsPerRecord
+	NoHeader        bool // treat the first row as data, not a header
+}
+
+// DefaultDialect is standard comma-separated CSV with variable-length rows
+// allowed, matching the behavior the tools had before dialects existed.
+func DefaultDialect() Dialect {
+	return Dialect{Delim: ',', FieldsPerRecord: -1}
+}
+
+func (d Dialect) applyToReader(r *csv.Reader) {
+	if d.Delim != 0 {
+		r.Comma = d.Delim
+	}
+	r.Comment = d.Comment
+	r.LazyQuotes = d.LazyQuotes
+	r.FieldsPerRecord = d.FieldsPerRecord
+}
+
+func (d Dialect) applyToWriter(w *csv.Writer) {
+	if d.Delim != 0 {
+		w.Comma = d.Delim
+	}
+}
+
+// NewWriter builds a *csv.Writer over w with d's delimiter applied.
+func NewWriter(w io.Writer, d Dialect) *csv.Writer {
+	writer := csv.NewWriter(w)
+	d.applyToWriter(writer)
+	return writer
+}
+
+// ParseRune decodes s as a single rune, as required by flags like -delim,
+// -quote, and -comment. An empty string decodes to 0 (meaning "unset").
+func ParseRune(s string) (rune, error) {
+	if s == "" {
+		return 0, nil
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError || size != len(s) {
+		return 0, fmt.Errorf("invalid value %q: must be a single character", s)
+	}
+	return r, nil
+}
+
+// DialectFromFlags builds a Dialect from the tools' common -delim, -quote,
+// -comment, -lazyquotes, -fields-per-record, and -no-header flag values.
+// quote is validated rather than applied, since encoding/csv always uses
+// '"' as the quote character.
+func DialectFromFlags(delim, quote, comment string, lazyQuotes bool, fieldsPerRecord int, noHeader bool) (Dialect, error) {
+	d := DefaultDialect()
+
+	delimRune, err := ParseRune(delim)
+	if err != nil {
+		return Dialect{}, fmt.Errorf("invalid -delim: %w", err)
+	}
+	if delimRune != 0 {
+		d.Delim = delimRune
+	}
+
+	if quote != "" {
+		quoteRune, err := ParseRune(quote)
+		if err != nil {
+			return Dialect{}, fmt.Errorf("invalid -quote: %w", err)
+		}
+		if quoteRune != '"' {
+			return Dialect{}, fmt.Errorf("invalid -quote %q: encoding/csv only supports '\"' as the quote character", quote)
+		}
+	}
+
+	if comment != "" {
+		commentRune, err := ParseRune(comment)
+		if err != nil {
+			return Dialect{}, fmt.Errorf("invalid -comment: %w", err)
+		}
+		d.Comment = commentRune
+	}
+
+	d.LazyQuotes = lazyQuotes
+	d.FieldsPerRecord = fieldsPerRecord
+	d.NoHeader = noHeader
+	return d, nil
+}
+
+// DialectFlags holds the flag values backing the tools' common CSV dialect
+// flags, registered by RegisterDialectFlags.
+type DialectFlags struct {
+	Delim           *string
+	Quote           *string
+	Comment         *string
+	LazyQuotes      *bool
+	FieldsPerRecord *int
+	NoHeader        *bool
+}
+
+// RegisterDialectFlags registers -delim, -quote, -comment, -lazyquotes,
+// -fields-per-record, and -no-header on fs, so every tool gets the same
+// dialect flags without redeclaring them.
+func RegisterDialectFlags(fs *flag.FlagSet) *DialectFlags {
+	return &DialectFlags{
+		Delim:           fs.String("delim", ",", "field delimiter (single character)"),
+		Quote:           fs.String("quote", "\"", `quote character (encoding/csv only supports '"')`),
+		Comment:         fs.String("comment", "", "comment character; lines starting with it are skipped (empty disables)"),
+		LazyQuotes:      fs.Bool("lazyquotes", false, "allow lazy handling of quotes"),
+		FieldsPerRecord: fs.Int("fields-per-record", -1, "expected fields per record (0 = infer from first record, negative = no check)"),
+		NoHeader:        fs.Bool("no-header", false, "treat the first row as data, not a header"),
+	}
+}
+
+// Dialect builds a Dialect from the registered flag values.
+func (f *DialectFlags) Dialect() (Dialect, error) {
+	return DialectFromFlags(*f.Delim, *f.Quote, *f.Comment, *f.LazyQuotes, *f.FieldsPerRecord, *f.NoHeader)
+}
+
+// OpenInput opens path for reading, or returns stdin if path is "" or "-".
+func OpenInput(path string) (io.ReadCloser, error) {
+	if path == "" || path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open input %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// OpenOutput creates path for writing, or returns stdout if path is "" or
+// "-". Closing the returned writer when path is stdout is a no-op.
+func OpenOutput(path string) (io.WriteCloser, error) {
+	if path == "" || path == "-" {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output %s: %w", path, err)
+	}
+	return file, nil
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,61 @@
+package csvpipe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeepColumns returns a Transform that keeps only the given 0-indexed
+// columns, in order. Columns past the end of a row are filled with "NULL",
+// matching the original truncator's handling of malformed rows.
+func KeepColumns(columns []int) Transform {
+	return func(row []string) ([]string, bool, error) {
+		out := make([]string, 0, len(columns))
+		for _, idx := range columns {
+			if idx < len(row) {
+				out = append(out, row[idx])
+			} else {
+				out = append(out, "NULL")
+			}
+		}
+		return out, true, nil
+	}
+}
+
+// CombineColumns returns a Transform that appends the trimmed, space-joined
+// values of col1Index and col2Index to each row.
+func CombineColumns(col1Index, col2Index int) Transform {
+	return func(row []string) ([]string, bool, error) {
+		if col1Index >= len(row) || col2Index >= len(row) {
+			return nil, false, fmt.Errorf("row has %d fields, cannot combine columns %d and %d", len(row), col1Index, col2Index)
+		}
+		out := make([]string, len(row), len(row)+1)
+		copy(out, row)
+		combined := strings.TrimSpace(row[col1Index]) + " " + strings.TrimSpace(row[col2Index])
+		return append(out, combined), true, nil
+	}
+}
+
+// FilterByColumnSet returns a Transform that keeps a row only if the
+// trimmed value at columnIndex is present in set.
+func FilterByColumnSet(columnIndex int, set map[string]struct{}) Transform {
+	return func(row []string) ([]string, bool, error) {
+		if columnIndex >= len(row) {
+			return nil, false, nil
+		}
+		_, ok := set[strings.TrimSpace(row[columnIndex])]
+		return row, ok, nil
+	}
+}
+
+// PartitionBy returns a function that extracts the partition key (the
+// trimmed value at columnIndex) from a row, for callers that route rows to
+// per-value sinks rather than a single Sink.
+func PartitionBy(columnIndex int) func(row []string) (string, error) {
+	return func(row []string) (string, error) {
+		if columnIndex >= len(row) {
+			return "", fmt.Errorf("row has %d fields, no column %d", len(row), columnIndex)
+		}
+		return strings.TrimSpace(row[columnIndex]), nil
+	}
+}
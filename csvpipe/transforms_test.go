@@ -0,0 +1,90 @@
+package csvpipe
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeepColumns(t *testing.T) {
+	transform := KeepColumns([]int{0, 2})
+
+	out, keep, err := transform([]string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !keep {
+		t.Fatalf("expected row to be kept")
+	}
+	if want := []string{"a", "c"}; !reflect.DeepEqual(out, want) {
+		t.Errorf("got %v, want %v", out, want)
+	}
+
+	out, _, err = transform([]string{"a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"a", "NULL"}; !reflect.DeepEqual(out, want) {
+		t.Errorf("got %v, want %v", out, want)
+	}
+}
+
+func TestCombineColumns(t *testing.T) {
+	transform := CombineColumns(0, 1)
+
+	out, keep, err := transform([]string{" Jane ", " Doe ", "jane@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !keep {
+		t.Fatalf("expected row to be kept")
+	}
+	want := []string{" Jane ", " Doe ", "jane@example.com", "Jane Doe"}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("got %v, want %v", out, want)
+	}
+
+	if _, _, err := transform([]string{"onlyone"}); err == nil {
+		t.Errorf("expected an error for a row missing the second column")
+	}
+}
+
+func TestFilterByColumnSet(t *testing.T) {
+	set := map[string]struct{}{"5551234": {}}
+	transform := FilterByColumnSet(1, set)
+
+	_, keep, err := transform([]string{"id1", " 5551234 "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !keep {
+		t.Errorf("expected row with matching value to be kept")
+	}
+
+	_, keep, err = transform([]string{"id2", "5559999"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keep {
+		t.Errorf("expected row with non-matching value to be dropped")
+	}
+
+	if _, keep, _ := transform([]string{"tooshort"}); keep {
+		t.Errorf("expected row missing the column to be dropped")
+	}
+}
+
+func TestPartitionBy(t *testing.T) {
+	partitionKey := PartitionBy(1)
+
+	value, err := partitionKey([]string{"id1", " us "})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != "us" {
+		t.Errorf("got %q, want %q", value, "us")
+	}
+
+	if _, err := partitionKey([]string{"tooshort"}); err == nil {
+		t.Errorf("expected an error for a row missing the partition column")
+	}
+}